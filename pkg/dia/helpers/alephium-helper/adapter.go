@@ -0,0 +1,189 @@
+package alephiumhelper
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	// ElexiumPairContractAddress is the factory contract for Elexium swap pairs.
+	ElexiumPairContractAddress = "21oY2dnZLzWBAXTT9Eo5GDnytPAJTAYdTsdW4fpTki4rm"
+	// CandyswapPairContractAddress is the factory contract for Candyswap swap pairs.
+	CandyswapPairContractAddress = "xJBpEfw4pK7BLC7vuyeNX2n5FeXS4zEYnnCB4WxMK6WV"
+
+	// ayinExchangeName is the exchange identifier AYIN trades are attributed to.
+	ayinExchangeName = "AYIN"
+	// elexiumExchangeName is the exchange identifier Elexium trades are attributed to.
+	elexiumExchangeName = "Elexium"
+	// candyswapExchangeName is the exchange identifier Candyswap trades are attributed to.
+	candyswapExchangeName = "Candyswap"
+)
+
+// DecimalsLookup resolves the number of decimals a token address's contract
+// was deployed with, so a DEXAdapter can normalize the raw on-chain integer
+// amounts in a swap event into human-scale Price/Volume.
+type DecimalsLookup func(tokenAddress string) (uint8, error)
+
+// DEXAdapter describes everything AlephiumClient needs to talk to a single
+// Alephium DEX: where its pair contracts come from and how to turn one of
+// its swap events into a dia.Trade. Built-in adapters are registered on
+// AlephiumClient at construction time, so adding support for a new DEX (or a
+// new pair-contract version of an existing one) never requires touching the
+// client itself.
+type DEXAdapter interface {
+	// Name returns the DEX's stable exchange identifier, used as
+	// dia.Trade.Source so downstream attribution is keyed on the exchange
+	// rather than on one of its pair-contract addresses.
+	Name() string
+	// FactoryAddress returns the address of the DEX's pair factory contract,
+	// whose sub-contracts are the individual swap pair contracts.
+	FactoryAddress() string
+	// PairMethodIndex returns the call-contract method index that resolves a
+	// pair's two underlying token addresses.
+	PairMethodIndex() int
+	// SwapEventIndex returns the event index a pair contract uses for swaps.
+	SwapEventIndex() int
+	// DecodeSwap decodes a single swap event into a dia.Trade, using the
+	// surrounding transaction for any details not carried by the event
+	// itself and decimals to normalize the event's raw token amounts.
+	DecodeSwap(event ContractEvent, tx TransactionDetailsResponse, decimals DecimalsLookup) (dia.Trade, error)
+}
+
+// ayinAdapter is the built-in DEXAdapter for AYIN's pair contracts.
+type ayinAdapter struct{}
+
+// NewAYINAdapter returns the DEXAdapter for AYIN.
+func NewAYINAdapter() DEXAdapter { return ayinAdapter{} }
+
+func (ayinAdapter) Name() string           { return ayinExchangeName }
+func (ayinAdapter) FactoryAddress() string { return AYINPairContractAddress }
+func (ayinAdapter) PairMethodIndex() int   { return TokenPairMethod }
+func (ayinAdapter) SwapEventIndex() int    { return SwapEventIndex }
+
+func (a ayinAdapter) DecodeSwap(event ContractEvent, tx TransactionDetailsResponse, decimals DecimalsLookup) (dia.Trade, error) {
+	return decodeSwapEvent(a, event, tx, decimals)
+}
+
+// elexiumAdapter is a placeholder DEXAdapter for Elexium's pair contracts.
+// Its PairMethodIndex, SwapEventIndex and swap event field layout have not
+// been confirmed against Elexium's actual pair contract ABI - they are not
+// guaranteed to match AYIN's, so DecodeSwap refuses to decode rather than
+// risk silently mis-reading amounts and addresses. Fill in the real values
+// and a matching decoder once Elexium's ABI has been verified.
+type elexiumAdapter struct{}
+
+// NewElexiumAdapter returns the DEXAdapter for Elexium. Not yet wired into
+// DefaultDEXAdapters(); see the elexiumAdapter doc comment.
+func NewElexiumAdapter() DEXAdapter { return elexiumAdapter{} }
+
+func (elexiumAdapter) Name() string           { return elexiumExchangeName }
+func (elexiumAdapter) FactoryAddress() string { return ElexiumPairContractAddress }
+func (elexiumAdapter) PairMethodIndex() int   { return TokenPairMethod }
+func (elexiumAdapter) SwapEventIndex() int    { return SwapEventIndex }
+
+func (elexiumAdapter) DecodeSwap(event ContractEvent, tx TransactionDetailsResponse, decimals DecimalsLookup) (dia.Trade, error) {
+	return dia.Trade{}, fmt.Errorf("elexium swap decoding is not implemented: pair ABI has not been verified")
+}
+
+// candyswapAdapter is a placeholder DEXAdapter for Candyswap's pair
+// contracts. Its PairMethodIndex, SwapEventIndex and swap event field layout
+// have not been confirmed against Candyswap's actual pair contract ABI - see
+// the elexiumAdapter doc comment for why DecodeSwap refuses to decode.
+type candyswapAdapter struct{}
+
+// NewCandyswapAdapter returns the DEXAdapter for Candyswap. Not yet wired
+// into DefaultDEXAdapters(); see the candyswapAdapter doc comment.
+func NewCandyswapAdapter() DEXAdapter { return candyswapAdapter{} }
+
+func (candyswapAdapter) Name() string           { return candyswapExchangeName }
+func (candyswapAdapter) FactoryAddress() string { return CandyswapPairContractAddress }
+func (candyswapAdapter) PairMethodIndex() int   { return TokenPairMethod }
+func (candyswapAdapter) SwapEventIndex() int    { return SwapEventIndex }
+
+func (candyswapAdapter) DecodeSwap(event ContractEvent, tx TransactionDetailsResponse, decimals DecimalsLookup) (dia.Trade, error) {
+	return dia.Trade{}, fmt.Errorf("candyswap swap decoding is not implemented: pair ABI has not been verified")
+}
+
+// DefaultDEXAdapters returns the DEX adapters this package ships with
+// verified swap decoding. Elexium and Candyswap are intentionally excluded
+// until their pair ABIs are confirmed (see elexiumAdapter/candyswapAdapter);
+// callers that want to enable them anyway can append NewElexiumAdapter()/
+// NewCandyswapAdapter() themselves, knowing DecodeSwap will error until
+// implemented.
+func DefaultDEXAdapters() []DEXAdapter {
+	return []DEXAdapter{
+		NewAYINAdapter(),
+	}
+}
+
+// decodeSwapEvent holds AYIN's verified pair event layout. Other adapters
+// must not delegate here unless their ABI has actually been confirmed to
+// match it.
+func decodeSwapEvent(adapter DEXAdapter, event ContractEvent, tx TransactionDetailsResponse, decimals DecimalsLookup) (dia.Trade, error) {
+	var trade dia.Trade
+
+	if len(event.Fields) < 4 {
+		return trade, fmt.Errorf("unexpected number of fields in swap event: %d", len(event.Fields))
+	}
+
+	tokenIn, err := AddressFromTokenId(event.Fields[0].Value)
+	if err != nil {
+		return trade, err
+	}
+	tokenOut, err := AddressFromTokenId(event.Fields[1].Value)
+	if err != nil {
+		return trade, err
+	}
+	amountIn, err := strconv.ParseFloat(event.Fields[2].Value, 64)
+	if err != nil {
+		return trade, err
+	}
+	amountOut, err := strconv.ParseFloat(event.Fields[3].Value, 64)
+	if err != nil {
+		return trade, err
+	}
+	if amountIn == 0 {
+		return trade, fmt.Errorf("swap event for pool %s has zero input amount", event.ContractAddress)
+	}
+
+	// A pair's two tokens are fixed, but which one was sold and which one
+	// was bought flips from swap to swap. Order base/quote lexicographically
+	// by address instead of by swap direction, so the same pool always
+	// reports the same base/quote pair and prices stay comparable.
+	base, quote := tokenIn, tokenOut
+	baseAmount, quoteAmount := amountIn, amountOut
+	if tokenOut < tokenIn {
+		base, quote = tokenOut, tokenIn
+		baseAmount, quoteAmount = amountOut, amountIn
+	}
+	if baseAmount == 0 {
+		return trade, fmt.Errorf("swap event for pool %s has zero base amount", event.ContractAddress)
+	}
+
+	// The event only carries raw, decimals-scaled integer amounts, so Price
+	// and Volume are meaningless until each token's own decimals are divided
+	// back out.
+	baseDecimals, err := decimals(base)
+	if err != nil {
+		return trade, fmt.Errorf("resolve decimals for base token %s: %w", base, err)
+	}
+	quoteDecimals, err := decimals(quote)
+	if err != nil {
+		return trade, fmt.Errorf("resolve decimals for quote token %s: %w", quote, err)
+	}
+	baseAmount /= math.Pow10(int(baseDecimals))
+	quoteAmount /= math.Pow10(int(quoteDecimals))
+
+	trade.PoolAddress = event.ContractAddress
+	trade.BaseToken = dia.Asset{Address: base, Blockchain: "Alephium"}
+	trade.QuoteToken = dia.Asset{Address: quote, Blockchain: "Alephium"}
+	trade.Volume = baseAmount
+	trade.Price = quoteAmount / baseAmount
+	trade.ForeignTradeID = tx.Hash
+	trade.Source = adapter.Name()
+
+	return trade, nil
+}
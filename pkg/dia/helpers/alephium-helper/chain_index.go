@@ -0,0 +1,32 @@
+package alephiumhelper
+
+// NumGroups is the number of shard groups in Alephium's sharded ledger.
+// Every block belongs to a (FromGroup, ToGroup) chain with both values in
+// [0, NumGroups), giving NumGroups*NumGroups = 16 independent chains.
+const NumGroups = 4
+
+// ChainIndex identifies one of Alephium's group-to-group chains.
+type ChainIndex struct {
+	FromGroup int
+	ToGroup   int
+}
+
+// GetAllChainIndices returns all 16 valid chain indices of the network.
+func GetAllChainIndices() []ChainIndex {
+	indices := make([]ChainIndex, 0, NumGroups*NumGroups)
+	for from := 0; from < NumGroups; from++ {
+		for to := 0; to < NumGroups; to++ {
+			indices = append(indices, ChainIndex{FromGroup: from, ToGroup: to})
+		}
+	}
+	return indices
+}
+
+// BlockEvents bundles the events found in a single block together with its
+// height, hash and the chain it was fetched from.
+type BlockEvents struct {
+	ChainIndex ChainIndex
+	Height     int
+	Hash       string
+	Events     []ContractEvent
+}
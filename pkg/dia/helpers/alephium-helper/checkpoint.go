@@ -0,0 +1,62 @@
+package alephiumhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Checkpoint persists the last height a Subscribe loop has acknowledged
+// processing for a given chain, so a restart can resume from there instead
+// of starting over at GetCurrentHeight - avoiding both missed events and
+// duplicate downstream publishes.
+type Checkpoint interface {
+	Load(chain ChainIndex) (height int, ok bool, err error)
+	Save(chain ChainIndex, height int) error
+}
+
+// FileCheckpoint is the default Checkpoint, storing one JSON file per chain
+// index in a directory.
+type FileCheckpoint struct {
+	dir string
+}
+
+// NewFileCheckpoint returns a FileCheckpoint that stores its files in dir.
+// The directory must already exist.
+func NewFileCheckpoint(dir string) *FileCheckpoint {
+	return &FileCheckpoint{dir: dir}
+}
+
+type checkpointData struct {
+	Height int `json:"height"`
+}
+
+func (f *FileCheckpoint) path(chain ChainIndex) string {
+	return fmt.Sprintf("%s/checkpoint-%d-%d.json", f.dir, chain.FromGroup, chain.ToGroup)
+}
+
+// Load returns the last saved height for chain, or ok=false if none was ever saved.
+func (f *FileCheckpoint) Load(chain ChainIndex) (int, bool, error) {
+	data, err := os.ReadFile(f.path(chain))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var cp checkpointData
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return 0, false, err
+	}
+	return cp.Height, true, nil
+}
+
+// Save persists height as the last acknowledged height for chain.
+func (f *FileCheckpoint) Save(chain ChainIndex, height int) error {
+	data, err := json.Marshal(checkpointData{Height: height})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.path(chain), data, 0644)
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
@@ -39,6 +40,11 @@ const (
 	DefaultSleepBetweenContractCalls = 300 // millisec
 	DefaultEventsLimit               = 100
 	DefaultSwapContractsLimit        = 100
+	DefaultMulticallBatchSize        = 32
+	// DefaultScanConcurrency bounds how many chains ScanAllChains scans at
+	// once, so the node never sees more than this many simultaneous bursts
+	// regardless of how many chain groups exist.
+	DefaultScanConcurrency = 4
 )
 
 // ALPHNativeToken: native alephium token - it has no related contract
@@ -50,16 +56,31 @@ var ALPHNativeToken = dia.Asset{
 	Name:     "Alephium",
 }
 
-// AlephiumClient: interaction with alephium REST API with urls from @BackendURL, @NodeURL contants
+// AlephiumClient: interaction with alephium REST API, rotating between the
+// endpoints of @Endpoints on failure
 type AlephiumClient struct {
 	Debug             bool
 	HTTPClient        *http.Client
+	Endpoints         EndpointPool
 	logger            *logrus.Entry
 	sleepBetweenCalls time.Duration
+	adapters          map[string]DEXAdapter
+	backendPool       *endpointRotator
+	nodePool          *endpointRotator
+	// TokenCache is consulted before any token metadata call-contract round
+	// trip and populated after a successful one. Nil disables caching.
+	TokenCache TokenMetadataCache
+	// Checkpoint lets Subscribe resume from the last acknowledged height
+	// after a restart instead of starting over at GetCurrentHeight. Nil
+	// means every Subscribe call starts from the current height.
+	Checkpoint Checkpoint
 }
 
-// NewAlephiumClient returns AlephiumClient
-func NewAlephiumClient(logger *logrus.Entry, sleepBetweenCalls time.Duration, debug bool) *AlephiumClient {
+// NewAlephiumClient returns an AlephiumClient that fails over between the
+// endpoints of the given pool and has the given DEX adapters registered.
+// Callers that only want a subset of the built-in DEXs enabled can pass a
+// filtered DefaultDEXAdapters() slice.
+func NewAlephiumClient(logger *logrus.Entry, sleepBetweenCalls time.Duration, debug bool, endpoints EndpointPool, adapters ...DEXAdapter) *AlephiumClient {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
@@ -71,88 +92,188 @@ func NewAlephiumClient(logger *logrus.Entry, sleepBetweenCalls time.Duration, de
 		Timeout:   10 * time.Second,
 	}
 
+	registry := make(map[string]DEXAdapter, len(adapters))
+	for _, adapter := range adapters {
+		registry[adapter.FactoryAddress()] = adapter
+	}
+
 	result := &AlephiumClient{
 		HTTPClient:        httpClient,
 		Debug:             debug,
+		Endpoints:         endpoints,
 		logger:            logger,
 		sleepBetweenCalls: sleepBetweenCalls,
+		adapters:          registry,
+		backendPool:       newEndpointRotator(endpoints.Backend),
+		nodePool:          newEndpointRotator(endpoints.Node),
 	}
 
 	return result
 }
 
-func (c *AlephiumClient) callAPI(request *http.Request, target interface{}) error {
-	if c.Debug {
-		dump, err := httputil.DumpRequestOut(request, true)
+// AdapterFor returns the DEXAdapter registered for a factory address, if any.
+func (c *AlephiumClient) AdapterFor(factoryAddress string) (DEXAdapter, bool) {
+	adapter, ok := c.adapters[factoryAddress]
+	return adapter, ok
+}
+
+// callAPI calls path on the healthiest endpoint of pool, retrying with
+// jittered exponential backoff and rotating to the next endpoint on 429s,
+// 5xxs and network errors, for at most DefaultMaxAttempts attempts in all. It
+// honors a Retry-After header given as a whole number of seconds; an HTTP-date
+// Retry-After is ignored and the backoff delay is used instead.
+func (c *AlephiumClient) callAPI(pool *endpointRotator, method, path string, body []byte, target interface{}) error {
+	endpoint := pool.current()
+
+	var lastErr error
+	for attempt := 0; attempt < DefaultMaxAttempts; attempt++ {
+		var reqBody io.Reader = http.NoBody
+		if body != nil {
+			reqBody = bytes.NewBuffer(body)
+		}
+		request, err := http.NewRequest(method, endpoint+path, reqBody)
 		if err != nil {
 			return err
 		}
-		log.Printf("DumpRequestOut: \n%s\n", string(dump))
-	}
 
-	resp, err := c.HTTPClient.Do(request)
-	if err != nil {
-		return err
-	}
+		if c.Debug {
+			dump, err := httputil.DumpRequestOut(request, true)
+			if err != nil {
+				return err
+			}
+			log.Printf("DumpRequestOut: \n%s\n", string(dump))
+		}
 
-	if c.Debug && resp != nil {
-		dump, err := httputil.DumpResponse(resp, true)
-		if err != nil {
-			return err
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(request)
+		requestDurationSeconds.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+		if err == nil && resp.StatusCode == http.StatusOK {
+			if c.Debug {
+				dump, dumpErr := httputil.DumpResponse(resp, true)
+				if dumpErr != nil {
+					return dumpErr
+				}
+				c.logger.Printf("\n%s\n", string(dump))
+			}
+
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			pool.recordSuccess(endpoint)
+			c.waiting()
+			return json.Unmarshal(data, target)
 		}
-		c.logger.Printf("\n%s\n", string(dump))
-	}
-	data, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		err = errors.New("not 200 http response code from api")
-		c.logger.
-			WithError(err).
-			WithField("resp.StatusCode", resp.StatusCode).
-			WithField("body", string(data)).
-			WithField("url", request.URL).
-			Error("failed to call api")
-		return err
-	}
+		var retryAfter time.Duration
+		if err == nil {
+			retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if !retryable {
+				apiErr := errors.New("not 200 http response code from api")
+				c.logger.
+					WithError(apiErr).
+					WithField("resp.StatusCode", resp.StatusCode).
+					WithField("body", string(data)).
+					WithField("url", endpoint+path).
+					Error("failed to call api")
+				return apiErr
+			}
+
+			if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil {
+				retryAfter = time.Duration(seconds) * time.Second
+			}
+			lastErr = fmt.Errorf("endpoint %s returned status %d", endpoint, resp.StatusCode)
+		} else {
+			lastErr = err
+		}
 
-	err = json.Unmarshal(data, &target)
-	if err != nil {
-		return err
+		pool.recordFailure(endpoint)
+		requestRetriesTotal.WithLabelValues(endpoint).Inc()
+
+		next := pool.next(endpoint)
+		if next != endpoint {
+			endpointFailoversTotal.WithLabelValues(endpoint, next).Inc()
+			endpoint = next
+		}
+
+		if attempt == DefaultMaxAttempts-1 {
+			break
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = backoffWithJitter(attempt)
+		}
+		time.Sleep(wait)
 	}
 
-	c.waiting()
+	c.logger.
+		WithError(lastErr).
+		WithField("path", path).
+		Error("exhausted all retries calling api")
+	return fmt.Errorf("exhausted retries calling %s: %w", path, lastErr)
+}
+
+// AdapterSubContracts is the set of swap pair contract addresses belonging
+// to a single registered DEXAdapter.
+type AdapterSubContracts struct {
+	Adapter      DEXAdapter
+	SubContracts []string
+}
 
-	return resp.Body.Close()
+// GetSwapPairsContractAddresses returns the swap pair contract addresses of
+// every registered DEX adapter, each tagged with the adapter it came from,
+// instead of only ever looking at a single hardcoded factory.
+func (c *AlephiumClient) GetSwapPairsContractAddresses(swapContractsLimit int) ([]AdapterSubContracts, error) {
+	result := make([]AdapterSubContracts, 0, len(c.adapters))
+	for _, adapter := range c.adapters {
+		subContracts, err := c.getSubContracts(adapter.FactoryAddress(), swapContractsLimit)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, AdapterSubContracts{Adapter: adapter, SubContracts: subContracts})
+	}
+	return result, nil
 }
 
-// GetSwapPairsContractAddresses returns swap contract addresses for alephium
-func (c *AlephiumClient) GetSwapPairsContractAddresses(swapContractsLimit int) (SubContractResponse, error) {
+// getSubContracts returns all sub-contract addresses of a single factory contract.
+func (c *AlephiumClient) getSubContracts(factoryAddress string, swapContractsLimit int) ([]string, error) {
 	var contractResponsePage1, contractResponsePage2 SubContractResponse
 
 	// Page 1
-	url := fmt.Sprintf("%s/contracts/%s/sub-contracts?limit=%d&page=1", BackendURL, AYINPairContractAddress, swapContractsLimit)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
-	err := c.callAPI(request, &contractResponsePage1)
+	path := fmt.Sprintf("/contracts/%s/sub-contracts?limit=%d&page=1", factoryAddress, swapContractsLimit)
+	err := c.callAPI(c.backendPool, "GET", path, nil, &contractResponsePage1)
 	if err != nil {
-		return contractResponsePage1, err
+		return nil, err
 	}
 
 	// Page 2
-	url = fmt.Sprintf("%s/contracts/%s/sub-contracts?limit=%d&page=2", BackendURL, AYINPairContractAddress, swapContractsLimit)
-	request, _ = http.NewRequest("GET", url, http.NoBody)
-	err = c.callAPI(request, &contractResponsePage2)
+	path = fmt.Sprintf("/contracts/%s/sub-contracts?limit=%d&page=2", factoryAddress, swapContractsLimit)
+	err = c.callAPI(c.backendPool, "GET", path, nil, &contractResponsePage2)
 	if err != nil {
-		return contractResponsePage1, err
+		return contractResponsePage1.SubContracts, err
 	}
 
-	for _, contract := range contractResponsePage2.SubContracts {
-		contractResponsePage1.SubContracts = append(contractResponsePage1.SubContracts, contract)
+	contractResponsePage1.SubContracts = append(contractResponsePage1.SubContracts, contractResponsePage2.SubContracts...)
+	return contractResponsePage1.SubContracts, nil
+}
+
+// GetSwapPairsChainIndex returns the chain index a swap pair contract lives
+// on, derived from its address rather than assumed to be group 0.
+func (c *AlephiumClient) GetSwapPairsChainIndex(contractAddress string) (ChainIndex, error) {
+	group, err := groupOfAddress(contractAddress)
+	if err != nil {
+		return ChainIndex{}, err
 	}
-	return contractResponsePage1, nil
+	return ChainIndex{FromGroup: int(group), ToGroup: int(group)}, nil
 }
 
-// GetTokenPairAddresses returns token address pair for swap contract address
-func (c *AlephiumClient) GetTokenPairAddresses(contractAddress string) ([]string, error) {
+// GetTokenPairAddresses returns the token address pair for a swap pair
+// contract, calling the pair-resolution method of the contract's own adapter
+// rather than assuming every DEX uses the same method index.
+func (c *AlephiumClient) GetTokenPairAddresses(contractAddress string, adapter DEXAdapter) ([]string, error) {
 	group, err := groupOfAddress(contractAddress)
 	if err != nil {
 		return nil, err
@@ -160,7 +281,7 @@ func (c *AlephiumClient) GetTokenPairAddresses(contractAddress string) ([]string
 	inputData := CallContractRequest{
 		Group:       int(group),
 		Address:     contractAddress,
-		MethodIndex: TokenPairMethod,
+		MethodIndex: adapter.PairMethodIndex(),
 	}
 	logger := c.logger.
 		WithField("function", "GetTokenPairAddresses").
@@ -172,14 +293,8 @@ func (c *AlephiumClient) GetTokenPairAddresses(contractAddress string) ([]string
 		logger.Fatalf("failed to marshal input data: %v", err)
 		return nil, err
 	}
-	url := fmt.Sprintf("%s/contracts/call-contract", NodeURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		logger.Fatalf("failed to create request: %v", err)
-		return nil, err
-	}
 	var response CallContractResult
-	err = c.callAPI(req, &response)
+	err = c.callAPI(c.nodePool, "POST", "/contracts/call-contract", jsonData, &response)
 
 	if err != nil {
 		logger.WithError(err).Error("failed to callApi")
@@ -218,6 +333,11 @@ func (c *AlephiumClient) GetTokenInfoForContractDecoded(contractAddress, blockch
 	if contractAddress == ALPHNativeToken.Address {
 		return &ALPHNativeToken, nil
 	}
+	if c.TokenCache != nil {
+		if asset, ok := c.TokenCache.Get(blockchain, contractAddress); ok {
+			return asset, nil
+		}
+	}
 	for i := 0; i < 3; i++ {
 		group, err := groupOfAddress(contractAddress)
 		if err != nil {
@@ -238,16 +358,9 @@ func (c *AlephiumClient) GetTokenInfoForContractDecoded(contractAddress, blockch
 		logger.Fatalf("failed to marshal input data: %v", err)
 		return nil, err
 	}
-	url := fmt.Sprintf("%s/contracts/multicall-contract", NodeURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-
-	if err != nil {
-		logger.Fatalf("failed to create request: %v", err)
-		return nil, err
-	}
 
 	var response MulticallContractResponse
-	err = c.callAPI(req, &response)
+	err = c.callAPI(c.nodePool, "POST", "/contracts/multicall-contract", jsonData, &response)
 
 	if err != nil {
 		logger.WithError(err).Error("failed to callApi")
@@ -274,40 +387,185 @@ func (c *AlephiumClient) GetTokenInfoForContractDecoded(contractAddress, blockch
 		output.Results = append(output.Results, result)
 	}
 	asset, err := c.decodeMulticallRequestToAssets(contractAddress, blockchain, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.TokenCache != nil {
+		if err := c.TokenCache.Set(blockchain, contractAddress, &asset); err != nil {
+			logger.WithError(err).Error("failed to cache token metadata")
+		}
+	}
+
+	return &asset, nil
+}
+
+// GetTokenInfoForContractsDecodedBatch resolves metadata for many tokens at
+// once. Cache hits (see TokenCache) skip the network entirely; misses are
+// grouped by groupOfAddress and packed in batches of DefaultMulticallBatchSize
+// addresses per /contracts/multicall-contract request.
+func (c *AlephiumClient) GetTokenInfoForContractsDecodedBatch(addresses []string, blockchain string) (map[string]*dia.Asset, error) {
+	return c.getTokenInfoForContractsDecodedBatch(addresses, blockchain, DefaultMulticallBatchSize)
+}
+
+func (c *AlephiumClient) getTokenInfoForContractsDecodedBatch(addresses []string, blockchain string, batchSize int) (map[string]*dia.Asset, error) {
+	logger := c.logger.WithField("function", "GetTokenInfoForContractsDecodedBatch")
+
+	result := make(map[string]*dia.Asset, len(addresses))
+	misses := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		if address == ALPHNativeToken.Address {
+			asset := ALPHNativeToken
+			result[address] = &asset
+			continue
+		}
+		if c.TokenCache != nil {
+			if asset, ok := c.TokenCache.Get(blockchain, address); ok {
+				result[address] = asset
+				continue
+			}
+		}
+		misses = append(misses, address)
+	}
+
+	groups := make(map[int][]string)
+	for _, address := range misses {
+		group, err := groupOfAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		groups[int(group)] = append(groups[int(group)], address)
+	}
+
+	for _, groupAddresses := range groups {
+		for start := 0; start < len(groupAddresses); start += batchSize {
+			end := start + batchSize
+			if end > len(groupAddresses) {
+				end = len(groupAddresses)
+			}
+
+			assets, err := c.multicallTokenInfo(groupAddresses[start:end], blockchain)
+			if err != nil {
+				logger.WithError(err).WithField("batch", groupAddresses[start:end]).Error("failed to batch-call token info")
+				return nil, err
+			}
+
+			for address, asset := range assets {
+				result[address] = asset
+				if c.TokenCache != nil {
+					if err := c.TokenCache.Set(blockchain, address, asset); err != nil {
+						logger.WithError(err).Error("failed to cache token metadata")
+					}
+				}
+			}
+		}
+	}
 
-	return &asset, err
+	return result, nil
 }
 
-// GetCurrentHeight returns the current height (block number) in Alephium network
-func (c *AlephiumClient) GetCurrentHeight() (int, error) {
+// multicallTokenInfo resolves symbol/name/decimals for a batch of addresses
+// in a single multicall-contract round trip. An address whose contract call
+// errors or fails to decode (e.g. a non-standard token missing one of the
+// expected methods) is logged and omitted from the result rather than
+// failing the whole batch.
+func (c *AlephiumClient) multicallTokenInfo(addresses []string, blockchain string) (map[string]*dia.Asset, error) {
+	logger := c.logger.WithField("function", "multicallTokenInfo")
+
+	inputData := make([]CallContractRequest, 0, len(addresses)*3)
+	for _, address := range addresses {
+		group, err := groupOfAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		for method := 0; method < 3; method++ {
+			inputData = append(inputData, CallContractRequest{
+				Group:       int(group),
+				Address:     address,
+				MethodIndex: method,
+			})
+		}
+	}
+
+	calls := Calls{Calls: inputData}
+	jsonData, err := json.Marshal(calls)
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal input data")
+		return nil, err
+	}
+
+	var response MulticallContractResponse
+	if err := c.callAPI(c.nodePool, "POST", "/contracts/multicall-contract", jsonData, &response); err != nil {
+		logger.WithError(err).Error("failed to callApi")
+		return nil, err
+	}
+
+	if len(response.Results) != len(addresses)*3 {
+		return nil, fmt.Errorf("expected %d multicall results, got %d", len(addresses)*3, len(response.Results))
+	}
+
+	assets := make(map[string]*dia.Asset, len(addresses))
+	for i, address := range addresses {
+		output := OutputResult{Address: address, Results: []OutputField{}}
+
+		failed := false
+		for _, row := range response.Results[i*3 : i*3+3] {
+			if row.Error != nil {
+				err := errors.New(*row.Error)
+				logger.WithError(err).WithField("contractAddress", address).Error("failed to get token info, skipping")
+				failed = true
+				break
+			}
+			output.Results = append(output.Results, OutputField{
+				ResponseResult: row.Type,
+				Field:          row.Returns[0],
+			})
+		}
+		if failed {
+			continue
+		}
+
+		asset, err := c.decodeMulticallRequestToAssets(address, blockchain, &output)
+		if err != nil {
+			logger.WithError(err).WithField("contractAddress", address).Error("failed to decode token info, skipping")
+			continue
+		}
+		assets[address] = &asset
+	}
+
+	return assets, nil
+}
+
+// GetCurrentHeight returns the current height (block number) of a given chain
+// (fromGroup->toGroup) in the Alephium network
+func (c *AlephiumClient) GetCurrentHeight(chain ChainIndex) (int, error) {
 	logger := c.logger.WithField("function", "GetLatestBlockHash")
 
-	url := fmt.Sprintf("%s/blockflow/chain-info?fromGroup=0&toGroup=0", NodeURL)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
+	path := fmt.Sprintf("/blockflow/chain-info?fromGroup=%d&toGroup=%d", chain.FromGroup, chain.ToGroup)
 
 	var response ChainInfoResponse
-	err := c.callAPI(request, &response)
+	err := c.callAPI(c.nodePool, "GET", path, nil, &response)
 
 	if err != nil {
-		logger.WithError(err).Error("failed to callApi")
+		logger.WithError(err).WithField("chainIndex", chain).Error("failed to callApi")
 		return 0, err
 	}
 
 	return response.CurrentHeight, nil
 }
 
-// GetBlockHashes returns all block hashes at a given height from REST API
-func (c *AlephiumClient) GetBlockHashes(height int) ([]string, error) {
+// GetBlockHashes returns all block hashes at a given height and chain from REST API
+func (c *AlephiumClient) GetBlockHashes(chain ChainIndex, height int) ([]string, error) {
 	logger := c.logger.WithField("function", "GetBlockHashes")
 
-	url := fmt.Sprintf("%s/blockflow/hashes?fromGroup=0&toGroup=0&height=%d", NodeURL, height)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
+	path := fmt.Sprintf("/blockflow/hashes?fromGroup=%d&toGroup=%d&height=%d", chain.FromGroup, chain.ToGroup, height)
 
 	var response BlockHashesResponse
-	err := c.callAPI(request, &response)
+	err := c.callAPI(c.nodePool, "GET", path, nil, &response)
 
 	if err != nil {
-		logger.WithError(err).Error("failed to callApi")
+		logger.WithError(err).WithField("chainIndex", chain).Error("failed to callApi")
 		return nil, err
 	}
 
@@ -315,17 +573,16 @@ func (c *AlephiumClient) GetBlockHashes(height int) ([]string, error) {
 }
 
 // GetContractEvents returns events included in a specific block from REST API
-func (c *AlephiumClient) GetBlockEvents(blockHash string) ([]ContractEvent, error) {
+func (c *AlephiumClient) GetBlockEvents(chain ChainIndex, blockHash string) ([]ContractEvent, error) {
 	logger := c.logger.WithField("function", "GetEvents")
 
-	url := fmt.Sprintf("%s/events/block-hash/%s?group=0", NodeURL, blockHash)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
+	path := fmt.Sprintf("/events/block-hash/%s?group=%d", blockHash, chain.FromGroup)
 
 	var response BlockEventsResponse
-	err := c.callAPI(request, &response)
+	err := c.callAPI(c.nodePool, "GET", path, nil, &response)
 
 	if err != nil {
-		logger.WithError(err).Error("failed to callApi")
+		logger.WithError(err).WithField("chainIndex", chain).Error("failed to callApi")
 		return nil, err
 	}
 
@@ -337,11 +594,10 @@ func (c *AlephiumClient) GetTransactionDetails(txnHash string) (TransactionDetai
 	logger := c.logger.WithField("function", "GetTransactionDetails")
 
 	// 'https://backend.mainnet.alephium.org/transactions/b9744b60b94a342c488dbf827747e5ac8ff8adabce48a72167f0ce3dfbe8291a
-	url := fmt.Sprintf("%s/transactions/%s", BackendURL, txnHash)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
+	path := fmt.Sprintf("/transactions/%s", txnHash)
 
 	var transactionDetailsResponse TransactionDetailsResponse
-	err := c.callAPI(request, &transactionDetailsResponse)
+	err := c.callAPI(c.backendPool, "GET", path, nil, &transactionDetailsResponse)
 
 	if err != nil {
 		logger.WithError(err).Error("failed to callApi")
@@ -350,24 +606,54 @@ func (c *AlephiumClient) GetTransactionDetails(txnHash string) (TransactionDetai
 	return transactionDetailsResponse, nil
 }
 
-func (s *AlephiumClient) FilterEvents(allEvents []ContractEvent, filter int) []ContractEvent {
+// FilterEvents returns the events matching the swap event index of the given adapter.
+func (s *AlephiumClient) FilterEvents(allEvents []ContractEvent, adapter DEXAdapter) []ContractEvent {
 	events := make([]ContractEvent, 0, len(allEvents))
 	for _, event := range allEvents {
-		if event.EventIndex == filter {
+		if event.EventIndex == adapter.SwapEventIndex() {
 			events = append(events, event)
 		}
 	}
 	return events
 }
 
+// GetSwapTrades filters allEvents down to the given adapter's swap events and
+// decodes each one into a dia.Trade via the adapter's own DecodeSwap. An
+// event that fails to decode is logged and skipped rather than aborting the
+// whole batch.
+func (c *AlephiumClient) GetSwapTrades(allEvents []ContractEvent, adapter DEXAdapter, tx TransactionDetailsResponse) []dia.Trade {
+	swapEvents := c.FilterEvents(allEvents, adapter)
+	decimals := func(tokenAddress string) (uint8, error) {
+		asset, err := c.GetTokenInfoForContractDecoded(tokenAddress, "Alephium")
+		if err != nil {
+			return 0, err
+		}
+		return asset.Decimals, nil
+	}
+
+	trades := make([]dia.Trade, 0, len(swapEvents))
+	for _, event := range swapEvents {
+		trade, err := adapter.DecodeSwap(event, tx, decimals)
+		if err != nil {
+			c.logger.
+				WithError(err).
+				WithField("function", "GetSwapTrades").
+				WithField("factoryAddress", adapter.FactoryAddress()).
+				Error("failed to decode swap event")
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	return trades
+}
+
 func (c *AlephiumClient) GetContractState(address string) (ContractStateResponse, error) {
 	logger := c.logger.WithField("function", "GetContractState")
 	// https://node.mainnet.alephium.org/contracts/22po9GJCMoLcYgXL3Znv2cSXcMnKmfm36MrBdqB4rSoKV/state
-	url := fmt.Sprintf("%s/contracts/%s/state", NodeURL, address)
-	request, _ := http.NewRequest("GET", url, http.NoBody)
+	path := fmt.Sprintf("/contracts/%s/state", address)
 
 	var contractStateResponse ContractStateResponse
-	err := c.callAPI(request, &contractStateResponse)
+	err := c.callAPI(c.nodePool, "GET", path, nil, &contractStateResponse)
 	if err != nil {
 		logger.WithError(err).Error("failed to callApi")
 		return contractStateResponse, err
@@ -416,3 +702,85 @@ func (s *AlephiumClient) decodeMulticallRequestToAssets(contractAddress, blockch
 func (c *AlephiumClient) waiting() {
 	time.Sleep(c.sleepBetweenCalls)
 }
+
+// chainScanResult is one chain's outcome from ScanAllChains, carried over an
+// internal results channel so each goroutine can report independently of the
+// others' progress.
+type chainScanResult struct {
+	chain       ChainIndex
+	blockEvents []BlockEvents
+	err         error
+}
+
+// ScanAllChains takes a one-shot snapshot of every one of the 4x4 Alephium
+// chains - the current height's block events for each - grouped by chain.
+// Heights are only comparable within a chain, not across chains, so this is
+// not a merged, height-ordered stream; callers that need a height-ordered
+// stream of a chain should poll that chain with Subscribe instead. At most
+// DefaultScanConcurrency chains are scanned at once, so the node never sees
+// more than that many simultaneous bursts regardless of how many chains
+// GetAllChainIndices returns.
+func (c *AlephiumClient) ScanAllChains() (map[ChainIndex][]BlockEvents, error) {
+	chains := GetAllChainIndices()
+	results := make(chan chainScanResult, len(chains))
+	sem := make(chan struct{}, DefaultScanConcurrency)
+
+	var wg sync.WaitGroup
+	for _, chain := range chains {
+		chain := chain
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			blockEvents, err := c.scanChain(chain)
+			results <- chainScanResult{chain: chain, blockEvents: blockEvents, err: err}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	scanned := make(map[ChainIndex][]BlockEvents, len(chains))
+	for result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		scanned[result.chain] = result.blockEvents
+	}
+
+	return scanned, nil
+}
+
+// scanChain fetches the current height, block hashes at that height, and the
+// events in each of those blocks for a single chain.
+func (c *AlephiumClient) scanChain(chain ChainIndex) ([]BlockEvents, error) {
+	height, err := c.GetCurrentHeight(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := c.GetBlockHashes(chain, height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockEvents := make([]BlockEvents, 0, len(hashes))
+	for _, hash := range hashes {
+		events, err := c.GetBlockEvents(chain, hash)
+		if err != nil {
+			return nil, err
+		}
+		blockEvents = append(blockEvents, BlockEvents{
+			ChainIndex: chain,
+			Height:     height,
+			Hash:       hash,
+			Events:     events,
+		})
+	}
+
+	return blockEvents, nil
+}
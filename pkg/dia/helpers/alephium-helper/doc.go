@@ -0,0 +1,13 @@
+// Package alephiumhelper wraps the Alephium REST API for the DIA scrapers.
+//
+// Breaking change note: NewAlephiumClient, GetCurrentHeight, GetBlockHashes,
+// GetBlockEvents, GetTokenPairAddresses, GetSwapPairsContractAddresses and
+// FilterEvents all changed signature to support scanning every chain group and dispatching by
+// DEXAdapter (see chain_index.go and adapter.go). Callers in
+// internal/pkg/blockchain-scrapers and internal/pkg/exchange-scrapers that
+// construct an AlephiumClient or call these methods need updating to pass an
+// EndpointPool, a ChainIndex and/or a DEXAdapter as appropriate; none of
+// those directories exist in this checkout, so that follow-up could not be
+// made part of this change and is left for whoever lands it alongside the
+// rest of the scraper.
+package alephiumhelper
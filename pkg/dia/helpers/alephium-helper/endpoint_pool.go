@@ -0,0 +1,130 @@
+package alephiumhelper
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts callAPI makes at a request,
+	// the first one included (across all endpoints in the pool), before giving up.
+	DefaultMaxAttempts = 6
+	// DefaultInitialBackoff is the first retry delay; it doubles on every
+	// subsequent attempt up to DefaultMaxBackoff.
+	DefaultInitialBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff caps the jittered exponential backoff between retries.
+	DefaultMaxBackoff = 3200 * time.Millisecond
+)
+
+var (
+	requestRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alephium_client_request_retries_total",
+		Help: "Number of retried Alephium API requests, by endpoint.",
+	}, []string{"endpoint"})
+	endpointFailoversTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alephium_client_endpoint_failovers_total",
+		Help: "Number of times the Alephium client rotated from one endpoint to another.",
+	}, []string{"from", "to"})
+	requestDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "alephium_client_request_duration_seconds",
+		Help: "Latency of Alephium API requests, by endpoint.",
+	}, []string{"endpoint"})
+)
+
+// EndpointPool is the set of Alephium REST API endpoints an AlephiumClient
+// can fail over between. Backend serves the indexer-backed endpoints (e.g.
+// transactions, sub-contracts); Node serves the full-node endpoints (e.g.
+// blockflow, contract calls).
+type EndpointPool struct {
+	Backend []string
+	Node    []string
+}
+
+// NewDefaultEndpointPool returns the pool pointing at Alephium's public
+// mainnet backend and node, with no failover peers.
+func NewDefaultEndpointPool() EndpointPool {
+	return EndpointPool{
+		Backend: []string{BackendURL},
+		Node:    []string{NodeURL},
+	}
+}
+
+// endpointHealth tracks the recent behaviour of a single endpoint.
+type endpointHealth struct {
+	consecutiveFailures int
+	lastSuccess         time.Time
+}
+
+// endpointRotator picks the healthiest endpoint out of a fixed list and
+// rotates away from one that just failed.
+type endpointRotator struct {
+	mu        sync.Mutex
+	endpoints []string
+	health    map[string]*endpointHealth
+}
+
+func newEndpointRotator(endpoints []string) *endpointRotator {
+	health := make(map[string]*endpointHealth, len(endpoints))
+	for _, endpoint := range endpoints {
+		health[endpoint] = &endpointHealth{}
+	}
+	return &endpointRotator{endpoints: endpoints, health: health}
+}
+
+// current returns the endpoint with the fewest consecutive failures.
+func (r *endpointRotator) current() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	best := r.endpoints[0]
+	for _, endpoint := range r.endpoints[1:] {
+		if r.health[endpoint].consecutiveFailures < r.health[best].consecutiveFailures {
+			best = endpoint
+		}
+	}
+	return best
+}
+
+// next returns the endpoint to try after endpoint has just failed.
+func (r *endpointRotator) next(endpoint string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.endpoints) == 1 {
+		return r.endpoints[0]
+	}
+	for i, e := range r.endpoints {
+		if e == endpoint {
+			return r.endpoints[(i+1)%len(r.endpoints)]
+		}
+	}
+	return r.endpoints[0]
+}
+
+func (r *endpointRotator) recordSuccess(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := r.health[endpoint]
+	h.consecutiveFailures = 0
+	h.lastSuccess = time.Now()
+}
+
+func (r *endpointRotator) recordFailure(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health[endpoint].consecutiveFailures++
+}
+
+// backoffWithJitter returns a jittered delay for the given retry attempt
+// (0-indexed), doubling from DefaultInitialBackoff and capped at DefaultMaxBackoff.
+func backoffWithJitter(attempt int) time.Duration {
+	backoff := DefaultInitialBackoff << attempt
+	if backoff > DefaultMaxBackoff || backoff <= 0 {
+		backoff = DefaultMaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2+1)))
+}
@@ -0,0 +1,155 @@
+package alephiumhelper
+
+import (
+	"context"
+	"time"
+)
+
+// maxSeenHashes bounds the per-chain dedup set a Subscribe loop keeps in
+// memory before resetting it.
+const maxSeenHashes = 10000
+
+// Subscribe owns the poll loop for a single chain and returns an ordered,
+// deduplicated stream of BlockEvents together with an error channel.
+//
+// If startHeight is negative, the loop resumes from Checkpoint (falling back
+// to GetCurrentHeight if nothing was ever checkpointed for chain). Whenever
+// the polled height has advanced by more than one block since the last
+// poll, every height in between is back-filled before moving on, and each
+// height's block hashes are deduplicated so Alephium's DAG returning the
+// same hash more than once does not produce duplicate events. The loop
+// stops when ctx is done.
+func (c *AlephiumClient) Subscribe(ctx context.Context, startHeight int, chain ChainIndex) (<-chan BlockEvents, <-chan error) {
+	events := make(chan BlockEvents)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+
+		nextHeight, err := c.resolveStartHeight(startHeight, chain)
+		if err != nil {
+			sendErr(ctx, errs, err)
+			return
+		}
+
+		seenHashes := make(map[string]struct{})
+		ticker := time.NewTicker(c.refreshDelay())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			height, err := c.GetCurrentHeight(chain)
+			if err != nil {
+				if !sendErr(ctx, errs, err) {
+					return
+				}
+				continue
+			}
+
+			for ; nextHeight <= height; nextHeight++ {
+				ok, cancelled := c.emitBlocksAtHeight(ctx, chain, nextHeight, seenHashes, events, errs)
+				if cancelled {
+					return
+				}
+				if !ok {
+					// Leave nextHeight unchanged so the next tick retries
+					// this height instead of silently skipping past it.
+					break
+				}
+
+				if c.Checkpoint != nil {
+					if err := c.Checkpoint.Save(chain, nextHeight); err != nil {
+						if !sendErr(ctx, errs, err) {
+							return
+						}
+					}
+				}
+
+				if len(seenHashes) > maxSeenHashes {
+					seenHashes = make(map[string]struct{})
+				}
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// resolveStartHeight returns the height a Subscribe loop should start
+// polling from: startHeight if given, otherwise the checkpointed height plus
+// one, otherwise the chain's current height.
+func (c *AlephiumClient) resolveStartHeight(startHeight int, chain ChainIndex) (int, error) {
+	if startHeight >= 0 {
+		return startHeight, nil
+	}
+
+	if c.Checkpoint != nil {
+		if height, ok, err := c.Checkpoint.Load(chain); err != nil {
+			return 0, err
+		} else if ok {
+			return height + 1, nil
+		}
+	}
+
+	return c.GetCurrentHeight(chain)
+}
+
+// emitBlocksAtHeight fetches and emits the not-yet-seen blocks at height. ok
+// is false when a fetch failed partway through, meaning height was not fully
+// processed and must be retried rather than checkpointed; cancelled is true
+// when ctx ended the wait early, meaning the caller must stop altogether.
+func (c *AlephiumClient) emitBlocksAtHeight(ctx context.Context, chain ChainIndex, height int, seenHashes map[string]struct{}, events chan<- BlockEvents, errs chan<- error) (ok bool, cancelled bool) {
+	hashes, err := c.GetBlockHashes(chain, height)
+	if err != nil {
+		if !sendErr(ctx, errs, err) {
+			return false, true
+		}
+		return false, false
+	}
+
+	for _, hash := range hashes {
+		if _, seen := seenHashes[hash]; seen {
+			continue
+		}
+
+		blockEvents, err := c.GetBlockEvents(chain, hash)
+		if err != nil {
+			if !sendErr(ctx, errs, err) {
+				return false, true
+			}
+			return false, false
+		}
+		seenHashes[hash] = struct{}{}
+
+		select {
+		case events <- BlockEvents{ChainIndex: chain, Height: height, Hash: hash, Events: blockEvents}:
+		case <-ctx.Done():
+			return false, true
+		}
+	}
+
+	return true, false
+}
+
+// refreshDelay returns how often Subscribe polls for a new height.
+func (c *AlephiumClient) refreshDelay() time.Duration {
+	return DefaultRefreshDelay * time.Millisecond
+}
+
+// sendErr sends err on errs unless ctx ends first, so a consumer that has
+// stopped draining errs (e.g. during a sustained outage) cannot block the
+// Subscribe goroutine forever. It returns false if ctx won the race, in
+// which case the caller must stop rather than keep going.
+func sendErr(ctx context.Context, errs chan<- error, err error) bool {
+	select {
+	case errs <- err:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package alephiumhelper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	bolt "go.etcd.io/bbolt"
+)
+
+// TokenMetadataCache caches decoded token metadata keyed by (blockchain,
+// address) so a scraper restart does not have to re-resolve every token it
+// already knows about.
+type TokenMetadataCache interface {
+	Get(blockchain, address string) (*dia.Asset, bool)
+	Set(blockchain, address string, asset *dia.Asset) error
+	Close() error
+}
+
+var tokenMetadataBucket = []byte("token_metadata")
+
+// BoltTokenMetadataCache is the default TokenMetadataCache, backed by a local BoltDB file.
+type BoltTokenMetadataCache struct {
+	db *bolt.DB
+}
+
+// NewBoltTokenMetadataCache opens (creating if necessary) a BoltDB-backed
+// TokenMetadataCache at the given file path.
+func NewBoltTokenMetadataCache(path string) (*BoltTokenMetadataCache, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tokenMetadataBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltTokenMetadataCache{db: db}, nil
+}
+
+func tokenCacheKey(blockchain, address string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", blockchain, address))
+}
+
+// Get returns the cached asset for (blockchain, address), if present.
+func (c *BoltTokenMetadataCache) Get(blockchain, address string) (*dia.Asset, bool) {
+	var asset dia.Asset
+	found := false
+
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(tokenMetadataBucket).Get(tokenCacheKey(blockchain, address))
+		if value == nil {
+			return nil
+		}
+		if err := json.Unmarshal(value, &asset); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+
+	if !found {
+		return nil, false
+	}
+	return &asset, true
+}
+
+// Set stores asset under (blockchain, address).
+func (c *BoltTokenMetadataCache) Set(blockchain, address string, asset *dia.Asset) error {
+	data, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokenMetadataBucket).Put(tokenCacheKey(blockchain, address), data)
+	})
+}
+
+// Close closes the underlying BoltDB file.
+func (c *BoltTokenMetadataCache) Close() error {
+	return c.db.Close()
+}